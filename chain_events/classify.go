@@ -0,0 +1,40 @@
+package chain_events
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrSporkHeightUnavailable is returned when the configured Access
+	// Node has no data for the requested height, typically because it
+	// precedes the root block of the current spork.
+	ErrSporkHeightUnavailable = fmt.Errorf("event data not available at this height, it may precede the current spork's root block")
+
+	// ErrSqliteLocked is returned when sqlite rejects a write because
+	// another connection holds the lock. It is expected to happen
+	// occasionally when the listener runs alongside other goroutines
+	// accessing the same sqlite file, and is safe to retry on the next
+	// polling round.
+	ErrSqliteLocked = fmt.Errorf("sqlite database is locked")
+)
+
+// classifyError maps raw errors coming out of the Flow client or the
+// datastore to one of the typed sentinel errors above (via %w, so
+// errors.Is still works), falling back to the original error untouched.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+
+	switch {
+	case strings.Contains(msg, "key not found"):
+		return fmt.Errorf("%w: %s", ErrSporkHeightUnavailable, msg)
+	case strings.Contains(msg, "database is locked"):
+		return fmt.Errorf("%w: %s", ErrSqliteLocked, msg)
+	default:
+		return err
+	}
+}
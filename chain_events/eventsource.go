@@ -0,0 +1,61 @@
+package chain_events
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+)
+
+// EventSource abstracts how the Listener obtains the current chain height
+// and event data for a height range. This lets the Listener's scheduling,
+// backoff and LockedStatus bookkeeping stay the same regardless of
+// whether events are pulled (PollingSource) or pushed (StreamingSource).
+type EventSource interface {
+	// LatestHeight returns the chain head as currently observed by this
+	// source.
+	LatestHeight(ctx context.Context) (uint64, error)
+
+	// Events returns all events of the given types within [start, end],
+	// both heights included.
+	Events(ctx context.Context, types []string, start, end uint64) ([]flow.Event, error)
+}
+
+// PollingSource is the original EventSource behaviour: a
+// GetLatestBlockHeader and a GetEventsForHeightRange call per round.
+type PollingSource struct {
+	fc FlowClient
+}
+
+// NewPollingSource wraps fc as an EventSource.
+func NewPollingSource(fc FlowClient) *PollingSource {
+	return &PollingSource{fc: fc}
+}
+
+func (p *PollingSource) LatestHeight(ctx context.Context) (uint64, error) {
+	h, err := p.fc.GetLatestBlockHeader(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	return h.Height, nil
+}
+
+func (p *PollingSource) Events(ctx context.Context, types []string, start, end uint64) ([]flow.Event, error) {
+	events := make([]flow.Event, 0)
+
+	for _, t := range types {
+		r, err := p.fc.GetEventsForHeightRange(ctx, client.EventRangeQuery{
+			Type:        t,
+			StartHeight: start,
+			EndHeight:   end,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range r {
+			events = append(events, b.Events...)
+		}
+	}
+
+	return events, nil
+}
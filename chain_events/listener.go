@@ -2,11 +2,12 @@ package chain_events
 
 import (
 	"context"
-	"log"
-	"os"
-	"strings"
+	"errors"
+	"math/rand"
+	"sync"
 	"time"
 
+	"github.com/flow-hydraulics/flow-wallet-api/logger"
 	"github.com/flow-hydraulics/flow-wallet-api/system"
 	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/client"
@@ -15,22 +16,52 @@ import (
 
 type GetEventTypes func() ([]string, error)
 
+// FlowClient is the subset of *client.Client the Listener depends on. It
+// exists so tests can inject a fake implementation without talking to a
+// real Access Node.
+type FlowClient interface {
+	GetEventsForHeightRange(ctx context.Context, query client.EventRangeQuery) ([]client.BlockEvents, error)
+	GetLatestBlockHeader(ctx context.Context, isSealed bool) (*flow.BlockHeader, error)
+}
+
 type Listener struct {
-	ticker         *time.Ticker
-	done           chan bool
-	logger         *log.Logger
-	fc             *client.Client
-	db             Store
-	getTypes       GetEventTypes
+	timer    *time.Timer
+	done     chan bool
+	log      logger.Logger
+	source   EventSource
+	db       Store
+	getTypes GetEventTypes
+
+	name           string
 	maxBlocks      uint64
 	interval       time.Duration
 	startingHeight uint64
 
+	// minInterval is the (short) delay used while catching up, i.e. when
+	// status.LatestHeight trails the chain head by more than maxBlocks.
+	// maxInterval caps how far the delay can grow under backoff. The
+	// effective delay starts at interval, doubles on every error up to
+	// maxInterval (backoffFactor), and resets to interval on the first
+	// successful round.
+	minInterval    time.Duration
+	maxInterval    time.Duration
+	backoffFactor  float64
+	jitterFraction float64
+
+	mu           sync.Mutex
+	currentDelay time.Duration
+
 	systemService *system.Service
 }
 
+// DefaultShard is the ListenerStatus row used by a Listener that wasn't
+// given a WithShard option, and the shard a pre-sharding cursor is
+// migrated into by MigrateToShardedStatus.
+const DefaultShard = "default"
+
 type ListenerStatus struct {
 	gorm.Model
+	ShardName    string `gorm:"uniqueIndex"`
 	LatestHeight uint64
 }
 
@@ -39,8 +70,8 @@ func (ListenerStatus) TableName() string {
 }
 
 func NewListener(
-	logger *log.Logger,
-	fc *client.Client,
+	log logger.Logger,
+	fc FlowClient,
 	db Store,
 	getTypes GetEventTypes,
 	maxDiff uint64,
@@ -48,14 +79,16 @@ func NewListener(
 	startingHeight uint64,
 	opts ...ListenerOption,
 ) *Listener {
-	if logger == nil {
-		logger = log.New(os.Stdout, "[EVENT-POLLER] ", log.LstdFlags|log.Lshortfile)
+	if log == nil {
+		log = logger.NewLogrusLogger(nil)
 	}
 
 	listener := &Listener{
 		nil, make(chan bool),
-		logger, fc, db, getTypes,
-		maxDiff, interval, startingHeight,
+		log, NewPollingSource(fc), db, getTypes,
+		DefaultShard, maxDiff, interval, startingHeight,
+		interval, interval * 8, 2.0, 0.2,
+		sync.Mutex{}, interval,
 		nil,
 	}
 
@@ -64,32 +97,34 @@ func NewListener(
 		opt(listener)
 	}
 
+	if listener.minInterval <= 0 {
+		listener.minInterval = interval
+	}
+	if listener.maxInterval < interval {
+		listener.maxInterval = interval
+	}
+
+	listener.currentDelay = interval
+	listener.log = listener.log.With("listener_id", listener.name)
+
 	return listener
 }
 
 func (l *Listener) run(ctx context.Context, start, end uint64) error {
-	events := make([]flow.Event, 0)
-
 	eventTypes, err := l.getTypes()
 	if err != nil {
 		return err
 	}
 
-	for _, t := range eventTypes {
-		r, err := l.fc.GetEventsForHeightRange(ctx, client.EventRangeQuery{
-			Type:        t,
-			StartHeight: start,
-			EndHeight:   end,
-		})
-		if err != nil {
-			return err
-		}
-		for _, b := range r {
-			events = append(events, b.Events...)
-		}
+	events, err := l.source.Events(ctx, eventTypes, start, end)
+	if err != nil {
+		return classifyError(err)
 	}
 
+	l.log.Debug("polled events", "block_start", start, "block_end", end, "event_count", len(events))
+
 	for _, event := range events {
+		l.log.Debug("triggering event", "event_type", event.Type, "block_start", start, "block_end", end)
 		Event.Trigger(event)
 	}
 
@@ -97,14 +132,60 @@ func (l *Listener) run(ctx context.Context, start, end uint64) error {
 }
 
 func (l *Listener) handleError(err error) {
-	l.logger.Println(err)
-	if strings.Contains(err.Error(), "key not found") {
-		l.logger.Println(`"key not found" error indicates data is not available at this height, please manually set correct starting height`)
+	switch {
+	case errors.Is(err, ErrSporkHeightUnavailable):
+		l.log.Error("event data not available at this height", "error", err,
+			"hint", "please manually set the correct starting height")
+	default:
+		l.log.Error("polling round failed", "error", err)
 	}
 }
 
+// CurrentDelay returns the listener's current backoff delay, before jitter
+// is applied. After a round that left the listener still catching up, the
+// next round waits minInterval instead. It is mainly useful for tests
+// asserting on backoff/recovery behaviour.
+func (l *Listener) CurrentDelay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.currentDelay
+}
+
+// withJitter randomizes d by up to +/- jitterFraction to avoid multiple
+// listeners polling the same Access Node in lockstep (thundering herd).
+func (l *Listener) withJitter(d time.Duration) time.Duration {
+	if l.jitterFraction <= 0 {
+		return d
+	}
+	jitter := time.Duration((rand.Float64()*2 - 1) * l.jitterFraction * float64(d))
+	d += jitter
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+func (l *Listener) recordSuccess() {
+	l.mu.Lock()
+	l.currentDelay = l.interval
+	l.mu.Unlock()
+}
+
+func (l *Listener) recordFailure() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	next := time.Duration(float64(l.currentDelay) * l.backoffFactor)
+	if next > l.maxInterval {
+		next = l.maxInterval
+	}
+	if next < l.interval {
+		next = l.interval
+	}
+	l.currentDelay = next
+}
+
 func (l *Listener) Start() *Listener {
-	if l.ticker != nil {
+	if l.timer != nil {
 		// Already started
 		return l
 	}
@@ -116,8 +197,8 @@ func (l *Listener) Start() *Listener {
 		// Skip LockError as it means another listener is already handling this
 	}
 
-	// TODO (latenssi): should use random intervals instead
-	l.ticker = time.NewTicker(l.interval)
+	l.currentDelay = l.interval
+	l.timer = time.NewTimer(l.withJitter(l.currentDelay))
 
 	go func() {
 		ctx, cancel := context.WithCancel(context.Background())
@@ -127,30 +208,37 @@ func (l *Listener) Start() *Listener {
 			select {
 			case <-l.done:
 				return
-			case <-l.ticker.C:
+			case <-l.timer.C:
 				// Check for maintenance mode
 				if l.waitMaintenance() {
+					l.timer.Reset(l.withJitter(l.CurrentDelay()))
 					continue
 				}
 
-				err := l.db.LockedStatus(func(status *ListenerStatus) error {
-					latestBlock, err := l.fc.GetLatestBlockHeader(ctx, true)
+				caughtUp := true
+
+				err := l.db.LockedStatus(l.name, func(status *ListenerStatus) error {
+					latestHeight, err := l.source.LatestHeight(ctx)
 					if err != nil {
-						return err
+						return classifyError(err)
 					}
 
-					if latestBlock.Height > status.LatestHeight {
-						start := status.LatestHeight + 1                  // LatestHeight has already been checked, add 1
-						end := min(latestBlock.Height, start+l.maxBlocks) // Limit maximum end
+					if latestHeight > status.LatestHeight {
+						start := status.LatestHeight + 1            // LatestHeight has already been checked, add 1
+						end := min(latestHeight, start+l.maxBlocks) // Limit maximum end
 						if err := l.run(ctx, start, end); err != nil {
-							if strings.Contains(err.Error(), "database is locked") {
+							if errors.Is(err, ErrSqliteLocked) {
 								// Sqlite throws this error from time to time when accessing it from
 								// multiple threads; listener is run in a separate thread.
+								l.log.Debug("sqlite busy, will retry next round", "error", err)
 								return nil
 							}
 							return err
 						}
 						status.LatestHeight = end
+						// Still behind by more than maxBlocks, keep polling at the
+						// (short) catch-up rate instead of the steady-state delay.
+						caughtUp = latestHeight-end <= l.maxBlocks
 					}
 
 					return nil
@@ -159,20 +247,29 @@ func (l *Listener) Start() *Listener {
 				if err != nil {
 					if _, isLockError := err.(*LockError); !isLockError {
 						l.handleError(err)
+						l.recordFailure()
 					}
 					// Skip on LockError as it means another listener is already handling this round
+				} else {
+					l.recordSuccess()
 				}
+
+				delay := l.CurrentDelay()
+				if !caughtUp {
+					delay = l.minInterval
+				}
+				l.timer.Reset(l.withJitter(delay))
 			}
 		}
 	}()
 
-	l.logger.Println("started")
+	l.log.Info("started")
 
 	return l
 }
 
 func (l *Listener) initHeight() error {
-	return l.db.LockedStatus(func(status *ListenerStatus) error {
+	return l.db.LockedStatus(l.name, func(status *ListenerStatus) error {
 		if l.startingHeight > 0 && status.LatestHeight < l.startingHeight-1 {
 			status.LatestHeight = l.startingHeight - 1
 		}
@@ -181,11 +278,11 @@ func (l *Listener) initHeight() error {
 			// If starting fresh, we need to start from the latest block as we can't
 			// know what is the root of the current spork.
 			// Data on Flow is only accessible for the current spork height.
-			latestBlock, err := l.fc.GetLatestBlockHeader(context.Background(), true)
+			latestHeight, err := l.source.LatestHeight(context.Background())
 			if err != nil {
-				return err
+				return classifyError(err)
 			}
-			status.LatestHeight = latestBlock.Height
+			status.LatestHeight = latestHeight
 		}
 
 		return nil
@@ -193,14 +290,21 @@ func (l *Listener) initHeight() error {
 }
 
 func (l *Listener) Stop() {
-	l.logger.Println("stopping...")
-	if l.ticker != nil {
-		l.ticker.Stop()
+	l.log.Info("stopping")
+	if l.timer != nil {
+		l.timer.Stop()
 	}
 	if l.done != nil {
 		l.done <- true
 	}
-	l.ticker = nil
+	l.timer = nil
+
+	// EventSource implementations such as StreamingSource hold a
+	// background subscription that otherwise keeps running (and leaking)
+	// past Stop.
+	if closer, ok := l.source.(interface{ Close() }); ok {
+		closer.Close()
+	}
 }
 
 func (l *Listener) waitMaintenance() bool {
@@ -0,0 +1,123 @@
+package chain_events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+)
+
+type fakeFlowClient struct {
+	mu        sync.Mutex
+	shouldErr bool
+	height    uint64
+}
+
+func (f *fakeFlowClient) setShouldErr(v bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.shouldErr = v
+}
+
+func (f *fakeFlowClient) GetLatestBlockHeader(ctx context.Context, isSealed bool) (*flow.BlockHeader, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.shouldErr {
+		return nil, errors.New("fake: access node unavailable")
+	}
+	return &flow.BlockHeader{Height: f.height}, nil
+}
+
+func (f *fakeFlowClient) GetEventsForHeightRange(ctx context.Context, q client.EventRangeQuery) ([]client.BlockEvents, error) {
+	return nil, nil
+}
+
+type fakeStore struct {
+	mu       sync.Mutex
+	statuses map[string]*ListenerStatus
+}
+
+func (s *fakeStore) LockedStatus(shard string, fn func(*ListenerStatus) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.statuses == nil {
+		s.statuses = make(map[string]*ListenerStatus)
+	}
+	status, ok := s.statuses[shard]
+	if !ok {
+		status = &ListenerStatus{ShardName: shard}
+		s.statuses[shard] = status
+	}
+	return fn(status)
+}
+
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met within %s", timeout)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+func TestListener_BackoffOnRepeatedErrors(t *testing.T) {
+	fc := &fakeFlowClient{shouldErr: true, height: 100}
+	store := &fakeStore{}
+
+	l := NewListener(
+		nil, fc, store,
+		func() ([]string, error) { return []string{"A.0000000000000001.Foo.Bar"}, nil },
+		10, 5*time.Millisecond, 50, // starting height 50, skips the initHeight access-node call
+		WithMinInterval(5*time.Millisecond),
+		WithMaxInterval(40*time.Millisecond),
+		WithBackoffFactor(2),
+		WithJitterFraction(0),
+	)
+
+	l.Start()
+	defer l.Stop()
+
+	waitFor(t, 2*time.Second, func() bool { return l.CurrentDelay() == 40*time.Millisecond })
+}
+
+func TestListener_RecoveryResetsDelay(t *testing.T) {
+	fc := &fakeFlowClient{shouldErr: true, height: 100}
+	store := &fakeStore{}
+
+	l := NewListener(
+		nil, fc, store,
+		func() ([]string, error) { return []string{"A.0000000000000001.Foo.Bar"}, nil },
+		10, 5*time.Millisecond, 50,
+		WithMinInterval(5*time.Millisecond),
+		WithMaxInterval(40*time.Millisecond),
+		WithBackoffFactor(2),
+		WithJitterFraction(0),
+	)
+
+	l.Start()
+	defer l.Stop()
+
+	waitFor(t, 2*time.Second, func() bool { return l.CurrentDelay() == 40*time.Millisecond })
+
+	fc.setShouldErr(false)
+
+	waitFor(t, 2*time.Second, func() bool { return l.CurrentDelay() == 5*time.Millisecond })
+}
+
+func TestListener_WithJitterStaysWithinBounds(t *testing.T) {
+	l := &Listener{interval: 100 * time.Millisecond, jitterFraction: 0.2}
+
+	base := 100 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		d := l.withJitter(base)
+		if d < 80*time.Millisecond || d > 120*time.Millisecond {
+			t.Fatalf("jittered delay %s out of +/-20%% bounds around %s", d, base)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package chain_events
+
+import "gorm.io/gorm"
+
+// MigrateToShardedStatus adds the shard_name column to chain_events_status
+// (via AutoMigrate, which also lays down its unique index on a fresh
+// database) and backfills any pre-existing, pre-sharding row into
+// DefaultShard so its LatestHeight carries over unchanged.
+//
+// Safe rollout: run this once, before deploying any Listener built with
+// WithShard. A deployment that only ever used the single, unsharded
+// Listener is unaffected by it (every row it sees is already DefaultShard).
+// Introduce shards one at a time by starting their Listener with
+// WithShard(name, predicate). A new shard's row is created with
+// LatestHeight 0 and does not inherit DefaultShard's LatestHeight: its
+// Listener starts from startingHeight-1 or, with starting height 0, from
+// the current chain head. Only remove the unsharded Listener once every
+// event type is covered by some shard's predicate.
+func MigrateToShardedStatus(db *gorm.DB) error {
+	if err := db.AutoMigrate(&ListenerStatus{}); err != nil {
+		return err
+	}
+
+	return db.Model(&ListenerStatus{}).
+		Where("shard_name = ? OR shard_name IS NULL", "").
+		Update("shard_name", DefaultShard).Error
+}
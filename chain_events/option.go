@@ -0,0 +1,60 @@
+package chain_events
+
+import "time"
+
+// ListenerOption can be used to customize the behaviour of a Listener at
+// construction time.
+type ListenerOption func(*Listener)
+
+// WithMinInterval sets the delay between polling rounds while the listener
+// is catching up, i.e. still trails the chain head by more than maxBlocks.
+// Defaults to the interval passed to NewListener.
+func WithMinInterval(d time.Duration) ListenerOption {
+	return func(l *Listener) {
+		l.minInterval = d
+	}
+}
+
+// WithMaxInterval caps the delay backoff can grow to after repeated
+// errors. Defaults to 8x the interval passed to NewListener.
+func WithMaxInterval(d time.Duration) ListenerOption {
+	return func(l *Listener) {
+		l.maxInterval = d
+	}
+}
+
+// WithBackoffFactor sets the multiplier applied to the current delay after
+// each failed polling round, up to WithMaxInterval. Defaults to 2.0.
+func WithBackoffFactor(f float64) ListenerOption {
+	return func(l *Listener) {
+		l.backoffFactor = f
+	}
+}
+
+// WithJitterFraction sets how much randomness (as a fraction of the
+// current delay) is added to each tick, to avoid multiple listeners
+// polling the same Access Node in lockstep. Defaults to 0.2 (+/- 20%).
+func WithJitterFraction(f float64) ListenerOption {
+	return func(l *Listener) {
+		l.jitterFraction = f
+	}
+}
+
+// WithName sets the listener's name, used as the listener_id log field
+// and as the shard name whose ListenerStatus row it locks and advances
+// (see WithShard). Defaults to DefaultShard.
+func WithName(name string) ListenerOption {
+	return func(l *Listener) {
+		l.name = name
+	}
+}
+
+// WithEventSource overrides how the Listener obtains events and the
+// current chain height. Defaults to a PollingSource wrapping the
+// FlowClient passed to NewListener; pass a StreamingSource built with
+// NewStreamingSource for push-based delivery.
+func WithEventSource(source EventSource) ListenerOption {
+	return func(l *Listener) {
+		l.source = source
+	}
+}
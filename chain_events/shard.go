@@ -0,0 +1,36 @@
+package chain_events
+
+// EventTypePredicate reports whether an event type belongs to a shard.
+type EventTypePredicate func(eventType string) bool
+
+// WithShard splits a Listener's event types across multiple instances for
+// horizontal scale-out: name becomes both the listener_id log field and the
+// ListenerStatus row the Listener locks and advances (see Store), and
+// predicate filters the full list returned by the getTypes passed to
+// NewListener down to the subset this shard owns. Shards must own disjoint,
+// collectively exhaustive subsets of event types, or events will be missed
+// or double-delivered.
+//
+// A Listener with no WithShard option uses DefaultShard, the row a
+// pre-sharding cursor is migrated into by MigrateToShardedStatus.
+func WithShard(name string, predicate EventTypePredicate) ListenerOption {
+	return func(l *Listener) {
+		l.name = name
+
+		getTypes := l.getTypes
+		l.getTypes = func() ([]string, error) {
+			types, err := getTypes()
+			if err != nil {
+				return nil, err
+			}
+
+			shardTypes := make([]string, 0, len(types))
+			for _, t := range types {
+				if predicate(t) {
+					shardTypes = append(shardTypes, t)
+				}
+			}
+			return shardTypes, nil
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package chain_events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithShard_FiltersEventTypesAndUsesShardAsName(t *testing.T) {
+	fc := &fakeFlowClient{height: 100}
+	store := &fakeStore{}
+
+	allTypes := []string{"A.0000000000000001.Foo.Bar", "A.0000000000000001.Baz.Qux"}
+
+	l := NewListener(
+		nil, fc, store,
+		func() ([]string, error) { return allTypes, nil },
+		10, 5*time.Millisecond, 50,
+		WithShard("foo-shard", func(eventType string) bool { return eventType == allTypes[0] }),
+	)
+
+	if l.name != "foo-shard" {
+		t.Fatalf("expected name %q, got %q", "foo-shard", l.name)
+	}
+
+	types, err := l.getTypes()
+	if err != nil {
+		t.Fatalf("getTypes returned error: %v", err)
+	}
+	if len(types) != 1 || types[0] != allTypes[0] {
+		t.Fatalf("expected getTypes filtered to %v, got %v", allTypes[:1], types)
+	}
+}
+
+func TestWithShard_EachShardLocksItsOwnStatusRow(t *testing.T) {
+	store := &fakeStore{}
+
+	fooClient := &fakeFlowClient{height: 100}
+	barClient := &fakeFlowClient{height: 100}
+
+	foo := NewListener(
+		nil, fooClient, store,
+		func() ([]string, error) { return []string{"A.0000000000000001.Foo.Bar"}, nil },
+		10, 5*time.Millisecond, 50,
+		WithShard("foo", func(string) bool { return true }),
+	)
+	bar := NewListener(
+		nil, barClient, store,
+		func() ([]string, error) { return []string{"A.0000000000000001.Baz.Qux"}, nil },
+		10, 5*time.Millisecond, 200,
+		WithShard("bar", func(string) bool { return true }),
+	)
+
+	foo.Start()
+	defer foo.Stop()
+	bar.Start()
+	defer bar.Stop()
+
+	waitFor(t, 2*time.Second, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		fooStatus, fooOK := store.statuses["foo"]
+		barStatus, barOK := store.statuses["bar"]
+		return fooOK && barOK && fooStatus.LatestHeight > 0 && barStatus.LatestHeight > 0
+	})
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.statuses["foo"].ShardName != "foo" || store.statuses["bar"].ShardName != "bar" {
+		t.Fatalf("expected distinct shard-keyed rows, got %+v", store.statuses)
+	}
+}
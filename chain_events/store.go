@@ -0,0 +1,26 @@
+package chain_events
+
+import "fmt"
+
+// Store persists each shard's ListenerStatus cursor and provides mutual
+// exclusion so only one process advances a given shard at a time (several
+// Listener instances, sharded by event type, run against the same Store).
+type Store interface {
+	// LockedStatus runs fn with exclusive access to the ListenerStatus row
+	// for shard, creating it (LatestHeight 0) on first use, and persists
+	// whatever changes fn makes to it before releasing the lock. It
+	// returns a *LockError, without calling fn, if another process already
+	// holds the lock for shard.
+	LockedStatus(shard string, fn func(status *ListenerStatus) error) error
+}
+
+// LockError is returned by Store.LockedStatus when another process already
+// holds the lock for the requested shard. Listener treats it as "someone
+// else is handling this round" rather than as a polling failure.
+type LockError struct {
+	Shard string
+}
+
+func (e *LockError) Error() string {
+	return fmt.Sprintf("chain_events: shard %q is locked by another process", e.Shard)
+}
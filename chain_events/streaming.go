@@ -0,0 +1,233 @@
+package chain_events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flow-hydraulics/flow-wallet-api/logger"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+)
+
+// EventStreamer is the subset of a push-based Access API client a
+// StreamingSource depends on: a long-lived subscription delivering blocks
+// of events as they are sealed, starting from startHeight. Implementations
+// are expected to close both channels once the subscription ends,
+// reporting the terminal error (if any) on errs first.
+type EventStreamer interface {
+	SubscribeEvents(ctx context.Context, startHeight uint64, types []string) (<-chan client.BlockEvents, <-chan error, error)
+}
+
+// StreamingSource is an EventSource backed by a persistent push
+// subscription. It serves Events/LatestHeight from its buffer whenever the
+// subscription is connected and has caught up to the requested range, and
+// falls back to a PollingSource (range queries) otherwise -- on first use,
+// and any time the subscription drops. The subscription is started from
+// where the first fallback query left off; after a drop it reconnects on
+// its own from the height after the last block it received.
+type StreamingSource struct {
+	streamer EventStreamer
+	fallback *PollingSource
+	log      logger.Logger
+
+	reconnectBackoff time.Duration
+	maxBackoff       time.Duration
+
+	mu        sync.Mutex
+	buf       []client.BlockEvents
+	connected bool
+	cancel    context.CancelFunc
+}
+
+// NewStreamingSource builds a StreamingSource. fallback serves catch-up
+// range queries and LatestHeight while the subscription is down.
+func NewStreamingSource(streamer EventStreamer, fallback *PollingSource, log logger.Logger) *StreamingSource {
+	if log == nil {
+		log = logger.NewLogrusLogger(nil)
+	}
+	return &StreamingSource{
+		streamer:         streamer,
+		fallback:         fallback,
+		log:              log,
+		reconnectBackoff: time.Second,
+		maxBackoff:       time.Minute,
+	}
+}
+
+func (s *StreamingSource) LatestHeight(ctx context.Context) (uint64, error) {
+	s.mu.Lock()
+	if s.connected && len(s.buf) > 0 {
+		h := s.buf[len(s.buf)-1].Height
+		s.mu.Unlock()
+		return h, nil
+	}
+	s.mu.Unlock()
+
+	return s.fallback.LatestHeight(ctx)
+}
+
+// Events returns events for [start, end]. When the stream is connected and
+// has already buffered the full range, it is served from the buffer;
+// otherwise it falls back to a range query and makes sure a subscription
+// resuming from end+1 is running, so later rounds are served by the
+// stream instead.
+func (s *StreamingSource) Events(ctx context.Context, types []string, start, end uint64) ([]flow.Event, error) {
+	if events, ok := s.fromBuffer(types, start, end); ok {
+		return events, nil
+	}
+
+	events, err := s.fallback.Events(ctx, types, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ensureSubscribed(end+1, types)
+
+	return events, nil
+}
+
+func (s *StreamingSource) fromBuffer(types []string, start, end uint64) ([]flow.Event, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop anything below start: already delivered, either by a
+	// previous call here or by a polling fallback taken while the buffer
+	// hadn't caught up yet. Without this, those entries survive an early
+	// "doesn't cover the range yet" return below and get re-emitted once
+	// the buffer does catch up.
+	trimmed := s.buf[:0:0]
+	for _, be := range s.buf {
+		if be.Height >= start {
+			trimmed = append(trimmed, be)
+		}
+	}
+	s.buf = trimmed
+
+	if !s.connected || len(s.buf) == 0 {
+		return nil, false
+	}
+	if s.buf[0].Height > start || s.buf[len(s.buf)-1].Height < end {
+		// Buffer doesn't (yet, or any more) cover the requested range.
+		return nil, false
+	}
+
+	wanted := make(map[string]bool, len(types))
+	for _, t := range types {
+		wanted[t] = true
+	}
+
+	events := make([]flow.Event, 0)
+	remaining := make([]client.BlockEvents, 0, len(s.buf))
+	for _, be := range s.buf {
+		if be.Height > end {
+			remaining = append(remaining, be)
+			continue
+		}
+		for _, e := range be.Events {
+			if wanted[e.Type] {
+				events = append(events, e)
+			}
+		}
+	}
+	s.buf = remaining
+
+	return events, true
+}
+
+func (s *StreamingSource) ensureSubscribed(fromHeight uint64, types []string) {
+	s.mu.Lock()
+	if s.connected || s.cancel != nil {
+		s.mu.Unlock()
+		return
+	}
+	subCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.mu.Unlock()
+
+	go s.run(subCtx, fromHeight, types)
+}
+
+func (s *StreamingSource) run(ctx context.Context, fromHeight uint64, types []string) {
+	backoff := s.reconnectBackoff
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		blocks, errs, err := s.streamer.SubscribeEvents(ctx, fromHeight, types)
+		if err != nil {
+			s.log.Warn("event stream subscribe failed, retrying", "error", err, "from_height", fromHeight, "retry_in", backoff)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = nextBackoff(backoff, s.maxBackoff)
+			continue
+		}
+
+		s.mu.Lock()
+		s.connected = true
+		s.mu.Unlock()
+		backoff = s.reconnectBackoff
+		s.log.Info("event stream connected", "from_height", fromHeight)
+
+		disconnected := false
+		for !disconnected {
+			select {
+			case <-ctx.Done():
+				s.mu.Lock()
+				s.connected = false
+				s.mu.Unlock()
+				return
+			case be, ok := <-blocks:
+				if !ok {
+					disconnected = true
+					break
+				}
+				s.mu.Lock()
+				s.buf = append(s.buf, be)
+				s.mu.Unlock()
+				fromHeight = be.Height + 1
+			case subErr, ok := <-errs:
+				if ok && subErr != nil {
+					s.log.Warn("event stream disconnected", "error", subErr)
+				}
+				disconnected = true
+			}
+		}
+
+		s.mu.Lock()
+		s.connected = false
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = nextBackoff(backoff, s.maxBackoff)
+	}
+}
+
+func nextBackoff(cur, max time.Duration) time.Duration {
+	next := cur * 2
+	if next > max {
+		return max
+	}
+	return next
+}
+
+// Close stops the underlying subscription, if one is running.
+func (s *StreamingSource) Close() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
@@ -0,0 +1,108 @@
+package chain_events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/client"
+)
+
+type fakeStreamer struct {
+	blocks chan client.BlockEvents
+	errs   chan error
+}
+
+func (f *fakeStreamer) SubscribeEvents(ctx context.Context, startHeight uint64, types []string) (<-chan client.BlockEvents, <-chan error, error) {
+	return f.blocks, f.errs, nil
+}
+
+func TestStreamingSource_FallsBackWhenNotConnected(t *testing.T) {
+	streamer := &fakeStreamer{blocks: make(chan client.BlockEvents), errs: make(chan error)}
+	fallback := NewPollingSource(&fakeFlowClient{height: 100})
+	s := NewStreamingSource(streamer, fallback, nil)
+
+	h, err := s.LatestHeight(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h != 100 {
+		t.Fatalf("expected fallback height 100, got %d", h)
+	}
+}
+
+func TestStreamingSource_BufferServesOnceConnected(t *testing.T) {
+	streamer := &fakeStreamer{blocks: make(chan client.BlockEvents, 8), errs: make(chan error, 1)}
+	fallback := NewPollingSource(&fakeFlowClient{height: 100})
+	s := NewStreamingSource(streamer, fallback, nil)
+
+	s.ensureSubscribed(11, []string{"A.01.Foo.Bar"})
+
+	streamer.blocks <- client.BlockEvents{Height: 11, Events: []flow.Event{{Type: "A.01.Foo.Bar"}}}
+	streamer.blocks <- client.BlockEvents{Height: 12, Events: []flow.Event{{Type: "A.01.Foo.Bar"}}}
+
+	deadline := time.Now().Add(2 * time.Second)
+	var events []flow.Event
+	var ok bool
+	for time.Now().Before(deadline) {
+		events, ok = s.fromBuffer([]string{"A.01.Foo.Bar"}, 11, 12)
+		if ok {
+			break
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	if !ok {
+		t.Fatalf("expected buffer to serve the requested range once connected")
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	s.Close()
+}
+
+// TestStreamingSource_StaleBufferEntriesAreNotReplayed exercises a round
+// that stalls partway through catch-up (buffer only covers part of the
+// requested range, so Events falls back to the range query) followed by a
+// round where the buffer has since caught up across the stale entries plus
+// the new ones -- the stale entries must not be re-emitted.
+func TestStreamingSource_StaleBufferEntriesAreNotReplayed(t *testing.T) {
+	streamer := &fakeStreamer{blocks: make(chan client.BlockEvents, 8), errs: make(chan error, 1)}
+	fallback := NewPollingSource(&fakeFlowClient{height: 100})
+	s := NewStreamingSource(streamer, fallback, nil)
+	defer s.Close()
+
+	s.mu.Lock()
+	s.connected = true
+	s.buf = []client.BlockEvents{
+		{Height: 21, Events: []flow.Event{{Type: "A.01.Foo.Bar"}}},
+		{Height: 22, Events: []flow.Event{{Type: "A.01.Foo.Bar"}}},
+	}
+	s.mu.Unlock()
+
+	// Round N: start=20,end=31. Buffer only covers up to 22, so this falls
+	// back to the range query; it must not consume or trim the buffer.
+	if _, ok := s.fromBuffer([]string{"A.01.Foo.Bar"}, 20, 31); ok {
+		t.Fatalf("expected buffer to not yet cover the full range")
+	}
+
+	s.mu.Lock()
+	for h := uint64(23); h <= 42; h++ {
+		s.buf = append(s.buf, client.BlockEvents{Height: h, Events: []flow.Event{{Type: "A.01.Foo.Bar"}}})
+	}
+	s.mu.Unlock()
+
+	// Round N+1: start=32,end=42. The buffer now covers 21-42, but heights
+	// 21-31 were already delivered via the round-N fallback and must not
+	// be replayed.
+
+	events, ok := s.fromBuffer([]string{"A.01.Foo.Bar"}, 32, 42)
+	if !ok {
+		t.Fatalf("expected buffer to serve round N+1")
+	}
+	if len(events) != 11 {
+		t.Fatalf("expected 11 events (heights 32-42), got %d", len(events))
+	}
+}
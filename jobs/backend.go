@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"fmt"
+
+	"github.com/flow-hydraulics/flow-wallet-api/logger"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// Backend selects which Store implementation NewStore constructs.
+type Backend string
+
+const (
+	BackendGorm   Backend = "gorm"
+	BackendRedis  Backend = "redis"
+	BackendMemory Backend = "memory"
+)
+
+// NewStore builds a Store for the given backend. db is only required for
+// BackendGorm and rdb only for BackendRedis; the other is ignored.
+func NewStore(backend Backend, db *gorm.DB, rdb *redis.Client, log logger.Logger) (Store, error) {
+	switch backend {
+	case BackendGorm, "":
+		return NewGormStore(db, log), nil
+	case BackendRedis:
+		return NewRedisStore(rdb, log), nil
+	case BackendMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown job store backend: %q", backend)
+	}
+}
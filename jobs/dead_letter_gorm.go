@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/flow-hydraulics/flow-wallet-api/datastore"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FailedJob records a job that exhausted its RetryPolicy, in its own
+// table so SchedulableJobs' query (which only ever looks at jobs.state)
+// doesn't need to know about it.
+type FailedJob struct {
+	gorm.Model
+	JobID     uuid.UUID `gorm:"type:uuid;index"`
+	Type      string
+	LastError string
+	Attempts  int
+}
+
+func (FailedJob) TableName() string {
+	return "failed_jobs"
+}
+
+func (s *GormStore) DeadLetterJob(j *Job, reason string) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		j.State = Failed
+		if err := tx.Save(j).Error; err != nil {
+			return err
+		}
+		return tx.Create(&FailedJob{
+			JobID:     j.ID,
+			Type:      j.Type,
+			LastError: reason,
+			Attempts:  j.ExecCount,
+		}).Error
+	})
+
+	s.log.With("job_id", j.ID).Warn("job dead-lettered", "reason", reason, "error", err)
+
+	return err
+}
+
+func (s *GormStore) DeadLetterJobs(o datastore.ListOptions) ([]DeadLetterRecord, error) {
+	var rows []FailedJob
+	err := s.db.
+		Order("created_at desc").
+		Limit(o.Limit).
+		Offset(o.Offset).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	dd := make([]DeadLetterRecord, len(rows))
+	for i, r := range rows {
+		dd[i] = DeadLetterRecord{
+			JobID:     r.JobID,
+			Type:      r.Type,
+			LastError: r.LastError,
+			Attempts:  r.Attempts,
+			CreatedAt: r.CreatedAt,
+		}
+	}
+	return dd, nil
+}
+
+func (s *GormStore) RequeueJob(id uuid.UUID) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("job_id = ?", id).Delete(&FailedJob{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Job{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{"state": Init, "exec_count": 0, "updated_at": time.Now()}).Error
+	})
+
+	s.log.With("job_id", id).Info("job requeued from dead letter queue", "error", err)
+
+	return err
+}
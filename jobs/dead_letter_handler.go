@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/flow-hydraulics/flow-wallet-api/datastore"
+	"github.com/google/uuid"
+)
+
+// ListDeadLetterJobsHandler lists dead-lettered jobs, most recently failed
+// first, paginated via the "limit"/"offset" query params.
+func ListDeadLetterJobsHandler(store Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		o := datastore.ListOptions{
+			Limit:  queryInt(r, "limit", 25),
+			Offset: queryInt(r, "offset", 0),
+		}
+
+		dd, err := store.DeadLetterJobs(o)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dd)
+	}
+}
+
+// RequeueJobHandler requeues the dead-lettered job whose id is the
+// second-to-last segment of the request path (e.g.
+// "/dead_letter_jobs/{id}/requeue"), resetting it to Init so
+// SchedulableJobs picks it up again.
+func RequeueJobHandler(store Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := requeuePathID(r)
+		if err != nil {
+			http.Error(w, "invalid job id", http.StatusBadRequest)
+			return
+		}
+
+		if err := store.RequeueJob(id); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// requeuePathID extracts the job id from a "/.../{id}/requeue" request
+// path. net/http's ServeMux path wildcards (r.PathValue) require Go 1.22;
+// this repo targets Go 1.21, so the segment is parsed by hand instead.
+func requeuePathID(r *http.Request) (uuid.UUID, error) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 {
+		return uuid.UUID{}, fmt.Errorf("invalid path: %s", r.URL.Path)
+	}
+	return uuid.Parse(parts[len(parts)-2])
+}
+
+func queryInt(r *http.Request, key string, fallback int) int {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,133 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/flow-hydraulics/flow-wallet-api/datastore"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const deadLetterKey = "jobs:dead_letter"
+
+func deadLetterRecordKey(id uuid.UUID) string {
+	return fmt.Sprintf("dead_letter:%s", id)
+}
+
+func (s *RedisStore) DeadLetterJob(j *Job, reason string) error {
+	ctx := context.Background()
+
+	prev, err := s.Job(j.ID)
+	if err != nil {
+		return err
+	}
+
+	j.State = Failed
+
+	rec := DeadLetterRecord{
+		JobID:     j.ID,
+		Type:      j.Type,
+		LastError: reason,
+		Attempts:  j.ExecCount,
+		CreatedAt: time.Now(),
+	}
+
+	jb, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	rb, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.rdb.TxPipeline()
+	pipe.Set(ctx, jobKey(j.ID), jb, 0)
+	if prev.State != Failed {
+		pipe.ZRem(ctx, stateKey(prev.State), j.ID.String())
+	}
+	pipe.ZAdd(ctx, stateKey(Failed), &redis.Z{Score: float64(rec.CreatedAt.Unix()), Member: j.ID.String()})
+	pipe.Set(ctx, deadLetterRecordKey(j.ID), rb, 0)
+	pipe.ZAdd(ctx, deadLetterKey, &redis.Z{Score: float64(rec.CreatedAt.Unix()), Member: j.ID.String()})
+	_, err = pipe.Exec(ctx)
+
+	s.log.With("job_id", j.ID).Warn("job dead-lettered", "reason", reason, "error", err)
+
+	return err
+}
+
+func (s *RedisStore) DeadLetterJobs(o datastore.ListOptions) ([]DeadLetterRecord, error) {
+	ctx := context.Background()
+
+	start := int64(o.Offset)
+	stop := int64(-1)
+	if o.Limit > 0 {
+		stop = start + int64(o.Limit) - 1
+	}
+
+	ids, err := s.rdb.ZRevRange(ctx, deadLetterKey, start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []DeadLetterRecord{}, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = fmt.Sprintf("dead_letter:%s", id)
+	}
+
+	raws, err := s.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	dd := make([]DeadLetterRecord, 0, len(raws))
+	for _, raw := range raws {
+		str, ok := raw.(string)
+		if !ok {
+			continue
+		}
+		var rec DeadLetterRecord
+		if err := json.Unmarshal([]byte(str), &rec); err != nil {
+			return nil, err
+		}
+		dd = append(dd, rec)
+	}
+
+	return dd, nil
+}
+
+func (s *RedisStore) RequeueJob(id uuid.UUID) error {
+	ctx := context.Background()
+
+	j, err := s.Job(id)
+	if err != nil {
+		return err
+	}
+
+	j.State = Init
+	j.ExecCount = 0
+	j.UpdatedAt = time.Now()
+
+	jb, err := json.Marshal(&j)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.rdb.TxPipeline()
+	pipe.Set(ctx, jobKey(id), jb, 0)
+	pipe.ZAdd(ctx, stateKey(Init), &redis.Z{Score: float64(j.UpdatedAt.Unix()), Member: id.String()})
+	pipe.ZRem(ctx, stateKey(Failed), id.String())
+	pipe.Del(ctx, deadLetterRecordKey(id))
+	pipe.ZRem(ctx, deadLetterKey, id.String())
+	_, err = pipe.Exec(ctx)
+
+	s.log.With("job_id", id).Info("job requeued from dead letter queue", "error", err)
+
+	return err
+}
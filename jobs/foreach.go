@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+// ForEachJob calls fn for every index in [0, numJobs), bounding the
+// number of concurrently in-flight calls to concurrency. It honors ctx
+// cancellation (no new calls are started once ctx or an errored fn
+// cancels the run) and returns the first error encountered, only after
+// every already-started call has finished.
+func ForEachJob(ctx context.Context, numJobs, concurrency int, fn func(ctx context.Context, idx int) error) error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, concurrency)
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for i := 0; i < numJobs; i++ {
+		select {
+		case <-runCtx.Done():
+			fail(runCtx.Err())
+			wg.Wait()
+			return firstErr
+		case sem <- struct{}{}:
+		}
+
+		// select picks at random when both cases are ready, so re-check
+		// cancellation before starting a call on the slot just acquired.
+		if err := runCtx.Err(); err != nil {
+			<-sem
+			fail(err)
+			break
+		}
+
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(runCtx, idx); err != nil {
+				fail(err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
@@ -0,0 +1,98 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEachJob_BoundsConcurrency(t *testing.T) {
+	const numJobs = 50
+	const concurrency = 5
+
+	var inFlight int32
+	var maxInFlight int32
+	var mu sync.Mutex
+
+	err := ForEachJob(context.Background(), numJobs, concurrency, func(ctx context.Context, idx int) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		mu.Lock()
+		if n > maxInFlight {
+			maxInFlight = n
+		}
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if maxInFlight > concurrency {
+		t.Fatalf("expected at most %d in-flight calls, saw %d", concurrency, maxInFlight)
+	}
+	if maxInFlight < 2 {
+		t.Fatalf("expected calls to actually run concurrently, max in-flight was %d", maxInFlight)
+	}
+}
+
+func TestForEachJob_PropagatesFirstErrorAndDrains(t *testing.T) {
+	const numJobs = 20
+
+	var started, completed int32
+	boom := errors.New("boom")
+
+	err := ForEachJob(context.Background(), numJobs, 4, func(ctx context.Context, idx int) error {
+		atomic.AddInt32(&started, 1)
+		defer atomic.AddInt32(&completed, 1)
+		if idx == 3 {
+			return boom
+		}
+		<-ctx.Done() // started calls keep running until cancellation propagates
+		return nil
+	})
+
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+	if s, c := atomic.LoadInt32(&started), atomic.LoadInt32(&completed); s != c {
+		t.Fatalf("expected every started call to finish before return, started %d, completed %d", s, c)
+	}
+}
+
+func BenchmarkForEachJob_ThroughputScalesWithConcurrency(b *testing.B) {
+	const numJobs = 200
+	work := func(ctx context.Context, idx int) error {
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+
+	for _, c := range []int{1, 4, 16} {
+		c := c
+		b.Run(fmtConcurrency(c), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := ForEachJob(context.Background(), numJobs, c, work); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func fmtConcurrency(c int) string {
+	switch c {
+	case 1:
+		return "concurrency=1"
+	case 4:
+		return "concurrency=4"
+	case 16:
+		return "concurrency=16"
+	default:
+		return "concurrency=?"
+	}
+}
@@ -0,0 +1,85 @@
+package jobs
+
+import "time"
+
+// Failed is a terminal state: the job exhausted its RetryPolicy (or hit a
+// Terminal-classified error) and was moved to the dead letter queue.
+// Unlike Error, SchedulableJobs never picks a Failed job back up.
+const Failed State = "failed"
+
+// ErrorClass says whether an error that caused a job to fail should be
+// retried or is permanent.
+type ErrorClass int
+
+const (
+	// Retryable errors are expected to succeed on a later attempt, e.g.
+	// transient network failures.
+	Retryable ErrorClass = iota
+	// Terminal errors will never succeed no matter how many times the
+	// job is retried, e.g. malformed input.
+	Terminal
+)
+
+// RetryPolicy controls how many times, and for which errors, a job gets
+// rescheduled after failing versus moved to the dead letter queue.
+type RetryPolicy struct {
+	// MaxAttempts caps how many times a job may be run, counting the
+	// first attempt. Zero means unlimited.
+	MaxAttempts int
+	// Backoff returns how long to wait before attempt n+1 becomes
+	// eligible again. A nil Backoff leaves ReSchedulableGracePeriod as
+	// the only backoff in effect.
+	Backoff func(attempt int) time.Duration
+	// Classify maps an error to Retryable/Terminal. A nil Classify
+	// treats every error as Retryable (so only MaxAttempts bounds it).
+	Classify func(err error) ErrorClass
+}
+
+func (p RetryPolicy) classify(attempt int, err error) ErrorClass {
+	if p.Classify != nil && p.Classify(err) == Terminal {
+		return Terminal
+	}
+	if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+		return Terminal
+	}
+	return Retryable
+}
+
+// RetryPolicies maps a job's Type to the RetryPolicy governing it. The
+// empty string key is the default policy used for types with no specific
+// entry (and for a nil/empty RetryPolicies, every error is Retryable).
+type RetryPolicies map[string]RetryPolicy
+
+func (pp RetryPolicies) forType(jobType string) RetryPolicy {
+	if p, ok := pp[jobType]; ok {
+		return p
+	}
+	return pp[""]
+}
+
+// recordFailure classifies execErr under the policy for j.Type and either
+// moves j to the dead letter queue (Terminal) or reschedules it
+// (Retryable). A policy with no Backoff reschedules exactly as before this
+// feature existed, i.e. purely via reSchedulableGracePeriod; one with a
+// Backoff instead withholds j from SchedulableJobs until Backoff(attempt)
+// has passed. It returns an error only if persisting that outcome itself
+// fails.
+func (pp RetryPolicies) recordFailure(store Store, j *Job, execErr error, reSchedulableGracePeriod time.Duration) error {
+	policy := pp.forType(j.Type)
+
+	if policy.classify(j.ExecCount, execErr) == Terminal {
+		return store.DeadLetterJob(j, execErr.Error())
+	}
+
+	if policy.Backoff != nil {
+		// SchedulableJobs treats an Error job as due once its UpdatedAt is
+		// older than reSchedulableGracePeriod; that period is subtracted
+		// here so the job becomes due exactly Backoff(attempt) from now,
+		// the delay RetryPolicy.Backoff documents.
+		notBefore := time.Now().Add(policy.Backoff(j.ExecCount) - reSchedulableGracePeriod)
+		return store.RescheduleJob(j, notBefore)
+	}
+
+	j.State = Error
+	return store.UpdateJob(j)
+}
@@ -0,0 +1,107 @@
+package jobs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flow-hydraulics/flow-wallet-api/datastore"
+	"github.com/google/uuid"
+)
+
+func TestRetryPolicy_Classify(t *testing.T) {
+	terminalErr := errors.New("malformed input")
+
+	policy := RetryPolicy{
+		MaxAttempts: 3,
+		Classify: func(err error) ErrorClass {
+			if err == terminalErr {
+				return Terminal
+			}
+			return Retryable
+		},
+	}
+
+	if got := policy.classify(1, errors.New("transient")); got != Retryable {
+		t.Fatalf("expected Retryable under MaxAttempts, got %v", got)
+	}
+	if got := policy.classify(3, errors.New("transient")); got != Terminal {
+		t.Fatalf("expected Terminal once attempt reaches MaxAttempts, got %v", got)
+	}
+	if got := policy.classify(1, terminalErr); got != Terminal {
+		t.Fatalf("expected Classify's Terminal verdict to win regardless of attempt, got %v", got)
+	}
+}
+
+func TestRetryPolicies_RecordFailure_TerminalDeadLetters(t *testing.T) {
+	s := NewMemoryStore()
+	j := &Job{ID: uuid.New(), Type: "test", State: Accepted, ExecCount: 1, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := s.InsertJob(j); err != nil {
+		t.Fatalf("InsertJob: %v", err)
+	}
+
+	policies := RetryPolicies{
+		"test": {Classify: func(error) ErrorClass { return Terminal }},
+	}
+
+	if err := policies.recordFailure(s, j, errors.New("boom"), time.Minute); err != nil {
+		t.Fatalf("recordFailure: %v", err)
+	}
+
+	if j.State != Failed {
+		t.Fatalf("expected job to be moved to Failed, got %s", j.State)
+	}
+
+	dd, err := s.DeadLetterJobs(datastore.ListOptions{})
+	if err != nil {
+		t.Fatalf("DeadLetterJobs: %v", err)
+	}
+	if len(dd) != 1 || dd[0].JobID != j.ID || dd[0].LastError != "boom" {
+		t.Fatalf("expected 1 dead letter record for %s with error %q, got %+v", j.ID, "boom", dd)
+	}
+}
+
+func TestRetryPolicies_RecordFailure_RetryableHonoursBackoff(t *testing.T) {
+	s := NewMemoryStore()
+	j := &Job{ID: uuid.New(), Type: "test", State: Accepted, ExecCount: 1, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := s.InsertJob(j); err != nil {
+		t.Fatalf("InsertJob: %v", err)
+	}
+
+	reSchedulableGracePeriod := time.Minute
+	backoff := 10 * time.Minute
+
+	policies := RetryPolicies{
+		"test": {Backoff: func(attempt int) time.Duration { return backoff }},
+	}
+
+	before := time.Now()
+	if err := policies.recordFailure(s, j, errors.New("transient"), reSchedulableGracePeriod); err != nil {
+		t.Fatalf("recordFailure: %v", err)
+	}
+
+	if j.State != Error {
+		t.Fatalf("expected job to be Error, got %s", j.State)
+	}
+
+	// Not yet schedulable: not enough time has passed for the backoff to
+	// have elapsed.
+	jj, err := s.SchedulableJobs(time.Minute, reSchedulableGracePeriod, datastore.ListOptions{})
+	if err != nil {
+		t.Fatalf("SchedulableJobs: %v", err)
+	}
+	for _, sj := range jj {
+		if sj.ID == j.ID {
+			t.Fatalf("expected job to not be schedulable immediately after a %s backoff", backoff)
+		}
+	}
+
+	// UpdatedAt should land reSchedulableGracePeriod before
+	// before+backoff, so SchedulableJobs' own aging check (UpdatedAt older
+	// than reSchedulableGracePeriod) fires exactly when backoff elapses.
+	wantNotBefore := before.Add(backoff)
+	gotNotBefore := j.UpdatedAt.Add(reSchedulableGracePeriod)
+	if d := gotNotBefore.Sub(wantNotBefore); d < -time.Second || d > time.Second {
+		t.Fatalf("expected job schedulable around %s, got %s (off by %s)", wantNotBefore, gotNotBefore, d)
+	}
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/flow-hydraulics/flow-wallet-api/datastore"
+)
+
+// SchedulerOptions configures RunSchedulableJobs.
+type SchedulerOptions struct {
+	AcceptedGracePeriod      time.Duration
+	ReSchedulableGracePeriod time.Duration
+	BatchSize                int
+	Concurrency              int
+
+	// RetryPolicies governs, per job Type, how many times a failed job
+	// is retried before it's moved to the dead letter queue. A nil/empty
+	// value retries every error indefinitely, matching the previous
+	// (policy-less) behaviour.
+	RetryPolicies RetryPolicies
+}
+
+// RunSchedulableJobs pulls one batch of schedulable jobs from store and
+// fans out up to opts.Concurrency of them concurrently via ForEachJob.
+// AcceptJob acts as the per-job lease: a worker that loses the race on
+// AcceptJob (another goroutine, or another instance for the Redis
+// backend) simply skips that job instead of treating it as an error.
+//
+// A job execute() fails is classified by opts.RetryPolicies and either
+// rescheduled (Error) or dead-lettered (Failed); that per-job outcome is
+// not propagated as the batch's error, only a failure to persist it is.
+func RunSchedulableJobs(ctx context.Context, store Store, opts SchedulerOptions, execute func(ctx context.Context, j Job) error) error {
+	jj, err := store.SchedulableJobs(
+		opts.AcceptedGracePeriod,
+		opts.ReSchedulableGracePeriod,
+		datastore.ListOptions{Limit: opts.BatchSize},
+	)
+	if err != nil {
+		return err
+	}
+
+	return ForEachJob(ctx, len(jj), opts.Concurrency, func(ctx context.Context, idx int) error {
+		j := jj[idx]
+
+		if err := store.AcceptJob(&j, opts.AcceptedGracePeriod); err != nil {
+			// Lost the race for this job; someone else already has it.
+			return nil
+		}
+
+		if execErr := execute(ctx, j); execErr != nil {
+			return opts.RetryPolicies.recordFailure(store, &j, execErr, opts.ReSchedulableGracePeriod)
+		}
+
+		return nil
+	})
+}
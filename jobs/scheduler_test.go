@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRunSchedulableJobs_AcceptJobPreventsDoubleExecution(t *testing.T) {
+	store := NewMemoryStore()
+
+	const numJobs = 30
+	// SchedulableJobs only returns Init/Accepted jobs whose UpdatedAt is
+	// older than AcceptedGracePeriod, so the fixtures need to be backdated
+	// past it to be picked up at all.
+	stale := time.Now().Add(-2 * time.Minute)
+	for i := 0; i < numJobs; i++ {
+		j := &Job{ID: uuid.New(), State: Init, CreatedAt: stale, UpdatedAt: stale}
+		if err := store.InsertJob(j); err != nil {
+			t.Fatalf("InsertJob: %v", err)
+		}
+	}
+
+	opts := SchedulerOptions{
+		AcceptedGracePeriod:      time.Minute,
+		ReSchedulableGracePeriod: time.Minute,
+		BatchSize:                numJobs,
+		Concurrency:              8,
+	}
+
+	execCounts := make(map[uuid.UUID]*int32)
+	var mu sync.Mutex
+	countFor := func(id uuid.UUID) *int32 {
+		mu.Lock()
+		defer mu.Unlock()
+		if c, ok := execCounts[id]; ok {
+			return c
+		}
+		c := new(int32)
+		execCounts[id] = c
+		return c
+	}
+
+	execute := func(ctx context.Context, j Job) error {
+		atomic.AddInt32(countFor(j.ID), 1)
+		return nil
+	}
+
+	// Simulate two worker instances racing over the same batch.
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := RunSchedulableJobs(context.Background(), store, opts, execute); err != nil {
+				t.Errorf("RunSchedulableJobs: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(execCounts) != numJobs {
+		t.Fatalf("expected all %d jobs to run exactly once, only %d ran", numJobs, len(execCounts))
+	}
+	for id, c := range execCounts {
+		if *c != 1 {
+			t.Fatalf("job %s executed %d times, want 1", id, *c)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/flow-hydraulics/flow-wallet-api/datastore"
+	"github.com/google/uuid"
+)
+
+// DeadLetterRecord describes a job that exhausted its RetryPolicy (see
+// RetryPolicy) and was moved out of the schedulable pool.
+type DeadLetterRecord struct {
+	JobID     uuid.UUID
+	Type      string
+	LastError string
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// Store is the persistence interface workers and the job API use to read
+// and mutate jobs. GormStore is the original (SQL) implementation;
+// RedisStore and MemoryStore are also available, see NewStore.
+type Store interface {
+	Jobs(o datastore.ListOptions) ([]Job, error)
+	Job(id uuid.UUID) (Job, error)
+	InsertJob(j *Job) error
+	UpdateJob(j *Job) error
+	AcceptJob(j *Job, acceptedGracePeriod time.Duration) error
+	SchedulableJobs(acceptedGracePeriod, reSchedulableGracePeriod time.Duration, o datastore.ListOptions) ([]Job, error)
+	Status() ([]StatusQuery, error)
+
+	// RescheduleJob marks j Error and sets its UpdatedAt to notBefore, so
+	// that SchedulableJobs (which only considers an Error job once its
+	// UpdatedAt is older than reSchedulableGracePeriod) won't return it
+	// again until notBefore plus reSchedulableGracePeriod has passed. Used
+	// to honour a RetryPolicy's Backoff.
+	RescheduleJob(j *Job, notBefore time.Time) error
+
+	// DeadLetterJob moves j to the dead letter queue, recording reason as
+	// its last error, and sets its state to Failed so SchedulableJobs
+	// stops returning it.
+	DeadLetterJob(j *Job, reason string) error
+	// DeadLetterJobs lists dead-lettered jobs, most recently failed first.
+	DeadLetterJobs(o datastore.ListOptions) ([]DeadLetterRecord, error)
+	// RequeueJob removes id from the dead letter queue and resets it to
+	// Init (with a fresh ExecCount) so SchedulableJobs picks it up again.
+	RequeueJob(id uuid.UUID) error
+}
@@ -5,16 +5,21 @@ import (
 	"time"
 
 	"github.com/flow-hydraulics/flow-wallet-api/datastore"
+	"github.com/flow-hydraulics/flow-wallet-api/logger"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type GormStore struct {
-	db *gorm.DB
+	db  *gorm.DB
+	log logger.Logger
 }
 
-func NewGormStore(db *gorm.DB) *GormStore {
-	return &GormStore{db}
+func NewGormStore(db *gorm.DB, log logger.Logger) *GormStore {
+	if log == nil {
+		log = logger.NewLogrusLogger(nil)
+	}
+	return &GormStore{db, log}
 }
 
 func (s *GormStore) Jobs(o datastore.ListOptions) (jj []Job, err error) {
@@ -32,15 +37,30 @@ func (s *GormStore) Job(id uuid.UUID) (j Job, err error) {
 }
 
 func (s *GormStore) InsertJob(j *Job) error {
-	return s.db.Create(j).Error
+	err := s.db.Create(j).Error
+	log := s.log.With("job_id", j.ID, "state", j.State)
+	if err != nil {
+		log.Error("job insert failed", "error", err)
+	} else {
+		log.Info("job inserted")
+	}
+	return err
 }
 
 func (s *GormStore) UpdateJob(j *Job) error {
-	return s.db.Save(j).Error
+	err := s.db.Save(j).Error
+	log := s.log.With("job_id", j.ID, "state", j.State, "exec_count", j.ExecCount)
+	if err != nil {
+		log.Error("job update failed", "error", err)
+	} else {
+		log.Debug("job updated")
+	}
+	return err
 }
 
 func (s *GormStore) AcceptJob(j *Job, acceptedGracePeriod time.Duration) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	log := s.log.With("job_id", j.ID)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
 		if err := s.db.First(&j, "id = ?", j.ID).Error; err != nil {
 			return err
 		}
@@ -56,6 +76,24 @@ func (s *GormStore) AcceptJob(j *Job, acceptedGracePeriod time.Duration) error {
 		}
 		return s.increaseExecCount(tx, j)
 	})
+	if err != nil {
+		log.Warn("job not accepted", "error", err)
+	} else {
+		log.Info("job accepted", "exec_count", j.ExecCount)
+	}
+	return err
+}
+
+func (s *GormStore) RescheduleJob(j *Job, notBefore time.Time) error {
+	err := s.db.Model(&Job{}).
+		Where("id = ?", j.ID).
+		Updates(map[string]interface{}{"state": Error, "updated_at": notBefore}).Error
+	if err == nil {
+		j.State = Error
+		j.UpdatedAt = notBefore
+	}
+	s.log.With("job_id", j.ID).Debug("job rescheduled after failure", "not_before", notBefore, "error", err)
+	return err
 }
 
 func (s *GormStore) increaseExecCount(tx *gorm.DB, j *Job) error {
@@ -78,6 +116,8 @@ func (s *GormStore) SchedulableJobs(acceptedGracePeriod, reSchedulableGracePerio
 		Offset(o.Offset).
 		Find(&jj).Error
 
+	s.log.Debug("schedulable jobs queried", "count", len(jj), "error", err)
+
 	return
 }
 
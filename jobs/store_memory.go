@@ -0,0 +1,231 @@
+package jobs
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/flow-hydraulics/flow-wallet-api/datastore"
+	"github.com/google/uuid"
+)
+
+// MemoryStore is an in-memory Store, intended for tests and for
+// single-instance deployments that don't want a database dependency.
+type MemoryStore struct {
+	mu          sync.Mutex
+	jobs        map[uuid.UUID]Job
+	deadLetters map[uuid.UUID]DeadLetterRecord
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs:        make(map[uuid.UUID]Job),
+		deadLetters: make(map[uuid.UUID]DeadLetterRecord),
+	}
+}
+
+func (s *MemoryStore) Jobs(o datastore.ListOptions) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jj := s.sortedByCreatedAtDesc()
+	return paginate(jj, o), nil
+}
+
+func (s *MemoryStore) Job(id uuid.UUID) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("job not found: %s", id)
+	}
+	return j, nil
+}
+
+func (s *MemoryStore) InsertJob(j *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[j.ID] = *j
+	return nil
+}
+
+func (s *MemoryStore) UpdateJob(j *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[j.ID]; !ok {
+		return fmt.Errorf("job not found: %s", j.ID)
+	}
+	s.jobs[j.ID] = *j
+	return nil
+}
+
+func (s *MemoryStore) AcceptJob(j *Job, acceptedGracePeriod time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.jobs[j.ID]
+	if !ok {
+		return fmt.Errorf("job not found: %s", j.ID)
+	}
+
+	tAccepted := time.Now().Add(-1 * acceptedGracePeriod)
+	if existing.State == Accepted && existing.UpdatedAt.After(tAccepted) {
+		return fmt.Errorf("job is already accepted: %s", j.ID)
+	}
+
+	existing.State = Accepted
+	existing.ExecCount++
+	existing.UpdatedAt = time.Now()
+
+	s.jobs[j.ID] = existing
+	*j = existing
+
+	return nil
+}
+
+func (s *MemoryStore) RescheduleJob(j *Job, notBefore time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.jobs[j.ID]
+	if !ok {
+		return fmt.Errorf("job not found: %s", j.ID)
+	}
+
+	existing.State = Error
+	existing.UpdatedAt = notBefore
+	s.jobs[j.ID] = existing
+	*j = existing
+
+	return nil
+}
+
+func (s *MemoryStore) SchedulableJobs(acceptedGracePeriod, reSchedulableGracePeriod time.Duration, o datastore.ListOptions) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t0 := time.Now()
+	tAccepted := t0.Add(-1 * acceptedGracePeriod)
+	tReschedulable := t0.Add(-1 * reSchedulableGracePeriod)
+
+	jj := make([]Job, 0)
+	for _, j := range s.jobs {
+		switch j.State {
+		case Init, Accepted:
+			if j.UpdatedAt.Before(tAccepted) {
+				jj = append(jj, j)
+			}
+		case Error, NoAvailableWorkers:
+			if j.UpdatedAt.Before(tReschedulable) {
+				jj = append(jj, j)
+			}
+		}
+	}
+
+	sort.Slice(jj, func(i, k int) bool { return jj[i].CreatedAt.After(jj[k].CreatedAt) })
+
+	return paginate(jj, o), nil
+}
+
+func (s *MemoryStore) Status() ([]StatusQuery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[string]int64)
+	for _, j := range s.jobs {
+		counts[string(j.State)]++
+	}
+
+	res := make([]StatusQuery, 0, len(counts))
+	for state, count := range counts {
+		res = append(res, StatusQuery{State: state, Count: count})
+	}
+	return res, nil
+}
+
+func (s *MemoryStore) DeadLetterJob(j *Job, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[j.ID]; !ok {
+		return fmt.Errorf("job not found: %s", j.ID)
+	}
+
+	j.State = Failed
+	s.jobs[j.ID] = *j
+	s.deadLetters[j.ID] = DeadLetterRecord{
+		JobID:     j.ID,
+		Type:      j.Type,
+		LastError: reason,
+		Attempts:  j.ExecCount,
+		CreatedAt: time.Now(),
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) DeadLetterJobs(o datastore.ListOptions) ([]DeadLetterRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dd := make([]DeadLetterRecord, 0, len(s.deadLetters))
+	for _, d := range s.deadLetters {
+		dd = append(dd, d)
+	}
+	sort.Slice(dd, func(i, k int) bool { return dd[i].CreatedAt.After(dd[k].CreatedAt) })
+
+	start := o.Offset
+	if start < 0 || start > len(dd) {
+		start = len(dd)
+	}
+	end := len(dd)
+	if o.Limit > 0 && start+o.Limit < end {
+		end = start + o.Limit
+	}
+
+	return dd[start:end], nil
+}
+
+func (s *MemoryStore) RequeueJob(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return fmt.Errorf("job not found: %s", id)
+	}
+
+	delete(s.deadLetters, id)
+
+	j.State = Init
+	j.ExecCount = 0
+	j.UpdatedAt = time.Now()
+	s.jobs[id] = j
+
+	return nil
+}
+
+func (s *MemoryStore) sortedByCreatedAtDesc() []Job {
+	jj := make([]Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jj = append(jj, j)
+	}
+	sort.Slice(jj, func(i, k int) bool { return jj[i].CreatedAt.After(jj[k].CreatedAt) })
+	return jj
+}
+
+func paginate(jj []Job, o datastore.ListOptions) []Job {
+	start := o.Offset
+	if start < 0 || start > len(jj) {
+		start = len(jj)
+	}
+	end := len(jj)
+	if o.Limit > 0 && start+o.Limit < end {
+		end = start + o.Limit
+	}
+	return jj[start:end]
+}
@@ -0,0 +1,122 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flow-hydraulics/flow-wallet-api/datastore"
+	"github.com/google/uuid"
+)
+
+func TestMemoryStore_AcceptJobRespectsGracePeriod(t *testing.T) {
+	s := NewMemoryStore()
+
+	j := &Job{ID: uuid.New(), State: Init, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := s.InsertJob(j); err != nil {
+		t.Fatalf("InsertJob: %v", err)
+	}
+
+	if err := s.AcceptJob(j, time.Minute); err != nil {
+		t.Fatalf("first AcceptJob should succeed: %v", err)
+	}
+	if j.State != Accepted || j.ExecCount != 1 {
+		t.Fatalf("expected state=Accepted exec_count=1, got state=%s exec_count=%d", j.State, j.ExecCount)
+	}
+
+	if err := s.AcceptJob(j, time.Minute); err == nil {
+		t.Fatalf("second AcceptJob within grace period should fail")
+	}
+
+	if err := s.AcceptJob(j, 0); err != nil {
+		t.Fatalf("AcceptJob with zero grace period should succeed: %v", err)
+	}
+	if j.ExecCount != 2 {
+		t.Fatalf("expected exec_count=2 after re-accepting, got %d", j.ExecCount)
+	}
+}
+
+func TestMemoryStore_SchedulableJobsFiltersByStateAndAge(t *testing.T) {
+	s := NewMemoryStore()
+
+	fresh := &Job{ID: uuid.New(), State: Init, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	stale := &Job{ID: uuid.New(), State: Init, CreatedAt: time.Now().Add(-time.Hour), UpdatedAt: time.Now().Add(-time.Hour)}
+	erroredRecent := &Job{ID: uuid.New(), State: Error, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	erroredStale := &Job{ID: uuid.New(), State: Error, CreatedAt: time.Now().Add(-time.Hour), UpdatedAt: time.Now().Add(-time.Hour)}
+
+	for _, j := range []*Job{fresh, stale, erroredRecent, erroredStale} {
+		if err := s.InsertJob(j); err != nil {
+			t.Fatalf("InsertJob: %v", err)
+		}
+	}
+
+	jj, err := s.SchedulableJobs(time.Minute, time.Minute, datastore.ListOptions{})
+	if err != nil {
+		t.Fatalf("SchedulableJobs: %v", err)
+	}
+
+	ids := make(map[uuid.UUID]bool, len(jj))
+	for _, j := range jj {
+		ids[j.ID] = true
+	}
+
+	if !ids[stale.ID] || !ids[erroredStale.ID] {
+		t.Fatalf("expected stale Init and Error jobs to be schedulable, got %+v", jj)
+	}
+	if ids[fresh.ID] || ids[erroredRecent.ID] {
+		t.Fatalf("expected jobs within their grace period to be excluded, got %+v", jj)
+	}
+}
+
+func TestMemoryStore_DeadLetterRoundTrip(t *testing.T) {
+	s := NewMemoryStore()
+
+	j := &Job{ID: uuid.New(), Type: "test", State: Error, ExecCount: 3, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := s.InsertJob(j); err != nil {
+		t.Fatalf("InsertJob: %v", err)
+	}
+
+	if err := s.DeadLetterJob(j, "exhausted retries"); err != nil {
+		t.Fatalf("DeadLetterJob: %v", err)
+	}
+	if j.State != Failed {
+		t.Fatalf("expected job to be moved to Failed, got %s", j.State)
+	}
+
+	dd, err := s.DeadLetterJobs(datastore.ListOptions{})
+	if err != nil {
+		t.Fatalf("DeadLetterJobs: %v", err)
+	}
+	if len(dd) != 1 || dd[0].JobID != j.ID || dd[0].LastError != "exhausted retries" || dd[0].Attempts != 3 {
+		t.Fatalf("expected 1 matching dead letter record, got %+v", dd)
+	}
+
+	jj, err := s.SchedulableJobs(time.Minute, 0, datastore.ListOptions{})
+	if err != nil {
+		t.Fatalf("SchedulableJobs: %v", err)
+	}
+	for _, sj := range jj {
+		if sj.ID == j.ID {
+			t.Fatalf("expected a Failed job to never be schedulable")
+		}
+	}
+
+	if err := s.RequeueJob(j.ID); err != nil {
+		t.Fatalf("RequeueJob: %v", err)
+	}
+
+	requeued, err := s.Job(j.ID)
+	if err != nil {
+		t.Fatalf("Job: %v", err)
+	}
+	if requeued.State != Init || requeued.ExecCount != 0 {
+		t.Fatalf("expected requeued job to be Init with exec_count=0, got state=%s exec_count=%d", requeued.State, requeued.ExecCount)
+	}
+
+	dd, err = s.DeadLetterJobs(datastore.ListOptions{})
+	if err != nil {
+		t.Fatalf("DeadLetterJobs: %v", err)
+	}
+	if len(dd) != 0 {
+		t.Fatalf("expected requeue to clear the dead letter record, got %+v", dd)
+	}
+}
@@ -0,0 +1,332 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/flow-hydraulics/flow-wallet-api/datastore"
+	"github.com/flow-hydraulics/flow-wallet-api/logger"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// RedisStore is a Store backed by Redis. Every job is stored as a JSON
+// blob under job:<id>; its id additionally lives in one sorted set per
+// state (jobs:state:<state>, scored by UpdatedAt) and one sorted set of
+// all jobs (jobs:all, scored by CreatedAt), so SchedulableJobs and Jobs
+// can use ZRANGEBYSCORE/ZREVRANGE instead of a full table scan.
+type RedisStore struct {
+	rdb *redis.Client
+	log logger.Logger
+}
+
+func NewRedisStore(rdb *redis.Client, log logger.Logger) *RedisStore {
+	if log == nil {
+		log = logger.NewLogrusLogger(nil)
+	}
+	return &RedisStore{rdb, log}
+}
+
+const allJobsKey = "jobs:all"
+
+func jobKey(id uuid.UUID) string {
+	return fmt.Sprintf("job:%s", id)
+}
+
+func stateKey(state State) string {
+	return fmt.Sprintf("jobs:state:%s", state)
+}
+
+func (s *RedisStore) Jobs(o datastore.ListOptions) (jj []Job, err error) {
+	ctx := context.Background()
+
+	start := int64(o.Offset)
+	stop := int64(-1)
+	if o.Limit > 0 {
+		stop = start + int64(o.Limit) - 1
+	}
+
+	ids, err := s.rdb.ZRevRange(ctx, allJobsKey, start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadJobs(ctx, ids)
+}
+
+func (s *RedisStore) Job(id uuid.UUID) (j Job, err error) {
+	raw, err := s.rdb.Get(context.Background(), jobKey(id)).Result()
+	if err == redis.Nil {
+		return Job{}, fmt.Errorf("job not found: %s", id)
+	}
+	if err != nil {
+		return Job{}, err
+	}
+	err = json.Unmarshal([]byte(raw), &j)
+	return
+}
+
+func (s *RedisStore) InsertJob(j *Job) error {
+	ctx := context.Background()
+
+	b, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.rdb.TxPipeline()
+	pipe.Set(ctx, jobKey(j.ID), b, 0)
+	pipe.ZAdd(ctx, stateKey(j.State), &redis.Z{Score: float64(j.UpdatedAt.Unix()), Member: j.ID.String()})
+	pipe.ZAdd(ctx, allJobsKey, &redis.Z{Score: float64(j.CreatedAt.Unix()), Member: j.ID.String()})
+	_, err = pipe.Exec(ctx)
+
+	if err != nil {
+		s.log.Error("job insert failed", "job_id", j.ID, "state", j.State, "error", err)
+	} else {
+		s.log.Info("job inserted", "job_id", j.ID, "state", j.State)
+	}
+
+	return err
+}
+
+func (s *RedisStore) UpdateJob(j *Job) error {
+	ctx := context.Background()
+
+	prev, err := s.Job(j.ID)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.rdb.TxPipeline()
+	pipe.Set(ctx, jobKey(j.ID), b, 0)
+	if prev.State != j.State {
+		pipe.ZRem(ctx, stateKey(prev.State), j.ID.String())
+	}
+	pipe.ZAdd(ctx, stateKey(j.State), &redis.Z{Score: float64(j.UpdatedAt.Unix()), Member: j.ID.String()})
+	_, err = pipe.Exec(ctx)
+
+	if err != nil {
+		s.log.Error("job update failed", "job_id", j.ID, "state", j.State, "exec_count", j.ExecCount, "error", err)
+	} else {
+		s.log.Debug("job updated", "job_id", j.ID, "state", j.State, "exec_count", j.ExecCount)
+	}
+
+	return err
+}
+
+func (s *RedisStore) RescheduleJob(j *Job, notBefore time.Time) error {
+	ctx := context.Background()
+
+	prev, err := s.Job(j.ID)
+	if err != nil {
+		return err
+	}
+
+	j.State = Error
+	j.UpdatedAt = notBefore
+
+	b, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.rdb.TxPipeline()
+	pipe.Set(ctx, jobKey(j.ID), b, 0)
+	if prev.State != Error {
+		pipe.ZRem(ctx, stateKey(prev.State), j.ID.String())
+	}
+	pipe.ZAdd(ctx, stateKey(Error), &redis.Z{Score: float64(notBefore.Unix()), Member: j.ID.String()})
+	_, err = pipe.Exec(ctx)
+
+	s.log.With("job_id", j.ID).Debug("job rescheduled after failure", "not_before", notBefore, "error", err)
+
+	return err
+}
+
+// acceptJobScript performs the same check-then-write AcceptJob needs as a
+// single atomic Redis operation, replacing GormStore's
+// gorm.Transaction + "exec_count = ? AND updated_at = ?" conditional
+// update:
+//
+//   - KEYS[1] is the job's blob key.
+//   - ARGV[1] is the accepted state name.
+//   - ARGV[2] is the JSON-encoded UpdatedAt the caller last observed; if
+//     the stored job's UpdatedAt no longer matches, someone else mutated
+//     it since and the script aborts (optimistic concurrency, same
+//     intent as the gorm WHERE clause).
+//   - ARGV[3]/ARGV[4] are the new UpdatedAt (RFC3339Nano, matching
+//     encoding/json's time.Time format) and its unix-seconds equivalent,
+//     used for the stored blob and the state sorted set score
+//     respectively.
+//
+// The state sorted set keys are derived from the decoded blob rather than
+// declared as KEYS, since the previous state isn't known up front.
+var acceptJobScript = redis.NewScript(`
+local raw = redis.call('GET', KEYS[1])
+if not raw then
+	return redis.error_reply('job not found')
+end
+
+local job = cjson.decode(raw)
+
+local accepted = ARGV[1]
+local expectedUpdatedAt = ARGV[2]
+local now = ARGV[3]
+local nowUnix = tonumber(ARGV[4])
+
+if cjson.encode(job.UpdatedAt) ~= expectedUpdatedAt then
+	return redis.error_reply('job was concurrently modified: ' .. job.ID)
+end
+
+local prevState = job.State
+
+job.State = accepted
+job.ExecCount = (job.ExecCount or 0) + 1
+job.UpdatedAt = now
+
+local encoded = cjson.encode(job)
+redis.call('SET', KEYS[1], encoded)
+
+if prevState ~= accepted then
+	redis.call('ZREM', 'jobs:state:' .. prevState, job.ID)
+end
+redis.call('ZADD', 'jobs:state:' .. accepted, nowUnix, job.ID)
+
+return encoded
+`)
+
+func (s *RedisStore) AcceptJob(j *Job, acceptedGracePeriod time.Duration) error {
+	ctx := context.Background()
+
+	current, err := s.Job(j.ID)
+	if err != nil {
+		return err
+	}
+
+	tAccepted := time.Now().Add(-1 * acceptedGracePeriod)
+	if current.State == Accepted && current.UpdatedAt.After(tAccepted) {
+		return fmt.Errorf("job is already accepted: %s", j.ID)
+	}
+
+	expectedUpdatedAt, err := json.Marshal(current.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	res, err := acceptJobScript.Run(ctx, s.rdb, []string{jobKey(j.ID)},
+		string(Accepted), string(expectedUpdatedAt), now.Format(time.RFC3339Nano), now.Unix(),
+	).Result()
+	if err != nil {
+		s.log.Warn("job not accepted", "job_id", j.ID, "error", err)
+		return err
+	}
+
+	encoded, ok := res.(string)
+	if !ok {
+		return fmt.Errorf("unexpected accept-job script result for job %s", j.ID)
+	}
+
+	var updated Job
+	if err := json.Unmarshal([]byte(encoded), &updated); err != nil {
+		return err
+	}
+	*j = updated
+
+	s.log.Info("job accepted", "job_id", j.ID, "exec_count", j.ExecCount)
+
+	return nil
+}
+
+func (s *RedisStore) SchedulableJobs(acceptedGracePeriod, reSchedulableGracePeriod time.Duration, o datastore.ListOptions) (jj []Job, err error) {
+	ctx := context.Background()
+
+	t0 := time.Now()
+	tAccepted := fmt.Sprintf("%d", t0.Add(-1*acceptedGracePeriod).Unix())
+	tReschedulable := fmt.Sprintf("%d", t0.Add(-1*reSchedulableGracePeriod).Unix())
+
+	ids := make([]string, 0)
+	for _, state := range []State{Init, Accepted} {
+		res, err := s.rdb.ZRangeByScore(ctx, stateKey(state), &redis.ZRangeBy{Min: "-inf", Max: tAccepted}).Result()
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, res...)
+	}
+	for _, state := range []State{Error, NoAvailableWorkers} {
+		res, err := s.rdb.ZRangeByScore(ctx, stateKey(state), &redis.ZRangeBy{Min: "-inf", Max: tReschedulable}).Result()
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, res...)
+	}
+
+	all, err := s.loadJobs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(all, func(i, k int) bool { return all[i].CreatedAt.After(all[k].CreatedAt) })
+
+	s.log.Debug("schedulable jobs queried", "count", len(all))
+
+	return paginate(all, o), nil
+}
+
+func (s *RedisStore) Status() ([]StatusQuery, error) {
+	ctx := context.Background()
+
+	res := make([]StatusQuery, 0)
+	for _, state := range []State{Init, Accepted, Error, NoAvailableWorkers, Failed} {
+		count, err := s.rdb.ZCard(ctx, stateKey(state)).Result()
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			continue
+		}
+		res = append(res, StatusQuery{State: string(state), Count: count})
+	}
+	return res, nil
+}
+
+func (s *RedisStore) loadJobs(ctx context.Context, ids []string) ([]Job, error) {
+	if len(ids) == 0 {
+		return []Job{}, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = fmt.Sprintf("job:%s", id)
+	}
+
+	raws, err := s.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	jj := make([]Job, 0, len(raws))
+	for _, raw := range raws {
+		str, ok := raw.(string)
+		if !ok {
+			// Job was deleted between the ZRANGE and the MGET.
+			continue
+		}
+		var j Job
+		if err := json.Unmarshal([]byte(str), &j); err != nil {
+			return nil, err
+		}
+		jj = append(jj, j)
+	}
+
+	return jj, nil
+}
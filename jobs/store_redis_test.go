@@ -0,0 +1,113 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/flow-hydraulics/flow-wallet-api/datastore"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+func newTestRedisStore(t *testing.T) *RedisStore {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	return NewRedisStore(rdb, nil)
+}
+
+func TestRedisStore_DeadLetterJobRemovesFromActualStateSet(t *testing.T) {
+	s := newTestRedisStore(t)
+
+	// Mirrors the real call path (AcceptJob then recordFailure): the job
+	// is Accepted, not Error/NoAvailableWorkers, when it's dead-lettered.
+	j := &Job{ID: uuid.New(), Type: "test", State: Accepted, ExecCount: 1, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := s.InsertJob(j); err != nil {
+		t.Fatalf("InsertJob: %v", err)
+	}
+
+	if err := s.DeadLetterJob(j, "boom"); err != nil {
+		t.Fatalf("DeadLetterJob: %v", err)
+	}
+
+	count, err := s.rdb.ZCard(context.Background(), stateKey(Accepted)).Result()
+	if err != nil {
+		t.Fatalf("ZCard: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected job to be removed from jobs:state:accepted, %d member(s) remain", count)
+	}
+
+	jj, err := s.SchedulableJobs(0, 0, datastore.ListOptions{})
+	if err != nil {
+		t.Fatalf("SchedulableJobs: %v", err)
+	}
+	for _, sj := range jj {
+		if sj.ID == j.ID {
+			t.Fatalf("expected dead-lettered job to never be schedulable again, got %+v", jj)
+		}
+	}
+}
+
+func TestRedisStore_DeadLetterRoundTrip(t *testing.T) {
+	s := newTestRedisStore(t)
+
+	j := &Job{ID: uuid.New(), Type: "test", State: Error, ExecCount: 3, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := s.InsertJob(j); err != nil {
+		t.Fatalf("InsertJob: %v", err)
+	}
+
+	if err := s.DeadLetterJob(j, "exhausted retries"); err != nil {
+		t.Fatalf("DeadLetterJob: %v", err)
+	}
+
+	dd, err := s.DeadLetterJobs(datastore.ListOptions{})
+	if err != nil {
+		t.Fatalf("DeadLetterJobs: %v", err)
+	}
+	if len(dd) != 1 || dd[0].JobID != j.ID || dd[0].LastError != "exhausted retries" || dd[0].Attempts != 3 {
+		t.Fatalf("expected 1 matching dead letter record, got %+v", dd)
+	}
+
+	if status, err := s.Status(); err != nil {
+		t.Fatalf("Status: %v", err)
+	} else {
+		found := false
+		for _, sq := range status {
+			if sq.State == string(Failed) {
+				found = true
+				if sq.Count != 1 {
+					t.Fatalf("expected 1 failed job in Status, got %d", sq.Count)
+				}
+			}
+		}
+		if !found {
+			t.Fatalf("expected Status to report the Failed state, got %+v", status)
+		}
+	}
+
+	if err := s.RequeueJob(j.ID); err != nil {
+		t.Fatalf("RequeueJob: %v", err)
+	}
+
+	requeued, err := s.Job(j.ID)
+	if err != nil {
+		t.Fatalf("Job: %v", err)
+	}
+	if requeued.State != Init || requeued.ExecCount != 0 {
+		t.Fatalf("expected requeued job to be Init with exec_count=0, got state=%s exec_count=%d", requeued.State, requeued.ExecCount)
+	}
+
+	dd, err = s.DeadLetterJobs(datastore.ListOptions{})
+	if err != nil {
+		t.Fatalf("DeadLetterJobs: %v", err)
+	}
+	if len(dd) != 0 {
+		t.Fatalf("expected requeue to clear the dead letter record, got %+v", dd)
+	}
+}
@@ -0,0 +1,19 @@
+// Package logger provides a small structured-logging abstraction so
+// packages don't need to depend directly on a specific logging library.
+// Fields are passed as alternating key-value pairs, the same convention
+// logrus and zerolog both use.
+package logger
+
+// Logger is a leveled, structured logger. Implementations should be safe
+// for concurrent use.
+type Logger interface {
+	Debug(msg string, kv ...interface{})
+	Info(msg string, kv ...interface{})
+	Warn(msg string, kv ...interface{})
+	Error(msg string, kv ...interface{})
+
+	// With returns a Logger that prepends kv to the fields of every
+	// subsequent call, useful for attaching request- or job-scoped
+	// context (e.g. job_id, listener_id) once.
+	With(kv ...interface{}) Logger
+}
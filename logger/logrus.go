@@ -0,0 +1,49 @@
+package logger
+
+import "github.com/sirupsen/logrus"
+
+// logrusLogger adapts *logrus.Entry to the Logger interface.
+type logrusLogger struct {
+	entry *logrus.Entry
+}
+
+// NewLogrusLogger wraps l (a nil l falls back to logrus.StandardLogger())
+// as a Logger.
+func NewLogrusLogger(l *logrus.Logger) Logger {
+	if l == nil {
+		l = logrus.StandardLogger()
+	}
+	return &logrusLogger{entry: logrus.NewEntry(l)}
+}
+
+func (l *logrusLogger) fields(kv []interface{}) logrus.Fields {
+	f := make(logrus.Fields, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			continue
+		}
+		f[key] = kv[i+1]
+	}
+	return f
+}
+
+func (l *logrusLogger) Debug(msg string, kv ...interface{}) {
+	l.entry.WithFields(l.fields(kv)).Debug(msg)
+}
+
+func (l *logrusLogger) Info(msg string, kv ...interface{}) {
+	l.entry.WithFields(l.fields(kv)).Info(msg)
+}
+
+func (l *logrusLogger) Warn(msg string, kv ...interface{}) {
+	l.entry.WithFields(l.fields(kv)).Warn(msg)
+}
+
+func (l *logrusLogger) Error(msg string, kv ...interface{}) {
+	l.entry.WithFields(l.fields(kv)).Error(msg)
+}
+
+func (l *logrusLogger) With(kv ...interface{}) Logger {
+	return &logrusLogger{entry: l.entry.WithFields(l.fields(kv))}
+}